@@ -95,7 +95,8 @@ func (s *Attribute) Space() *Dataspace {
 	return nil
 }
 
-// Read reads raw data from a attribute into a buffer.
+// Read reads raw data from a attribute into a buffer. Variable-length
+// types (VL strings, H5T_VLEN) are rejected; use ReadVL for those.
 func (s *Attribute) Read(data interface{}) error {
 	v := reflect.ValueOf(data)
 
@@ -103,6 +104,13 @@ func (s *Attribute) Read(data interface{}) error {
 		return fmt.Errorf("Attribute: Read (non-pointer %v )", v.Kind())
 	}
 
+	dtype := s.GetType()
+	isVL := isVariableLength(dtype)
+	dtype.Close()
+	if isVL {
+		return fmt.Errorf("Attribute: Read: variable-length type, use ReadVL")
+	}
+
 	var addr uintptr
 	var err error
 	var typ *Datatype
@@ -113,7 +121,7 @@ func (s *Attribute) Read(data interface{}) error {
 		if v.Elem().Len() == 0 {
 			return nil
 		}
-		typ, err = NewDataTypeFromType(v.Type().Elem().Elem())
+		typ, err = datatypeFor(v.Type().Elem().Elem())
 		addr = v.Elem().UnsafeAddr()
 
 	case reflect.String: //Special Case read in order to trim null chars
@@ -132,7 +140,7 @@ func (s *Attribute) Read(data interface{}) error {
 		v.Elem().SetString(strings.Trim(buf, "\x00"))
 		return nil
 	case reflect.Slice:
-		typ, err = NewDataTypeFromType(v.Type().Elem().Elem())
+		typ, err = datatypeFor(v.Type().Elem().Elem())
 		if ln := int(C.H5Aget_storage_size(s.id)) / int(typ.Size()); ln <= v.Elem().Cap() {
 			v.Elem().SetLen(ln)
 		} else {
@@ -190,10 +198,15 @@ func (s *Attribute) Write(data interface{}) error {
 		addr = v.Elem().UnsafeAddr()
 	}
 
-	dtype, err := NewDataTypeFromType(v.Elem().Type())
+	elemType := v.Elem().Type()
+	if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array {
+		elemType = elemType.Elem()
+	}
+	dtype, err := datatypeFor(elemType)
 	if err != nil {
 		return fmt.Errorf("Datatype error: %v", err)
 	}
+	defer dtype.Close()
 
 	return h5err(C.H5Awrite(s.id, dtype.id, unsafe.Pointer(addr)))
 }
@@ -0,0 +1,90 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// NewCompoundTypeFromStruct builds an H5T_COMPOUND datatype matching rt's
+// exact in-memory layout, inserting each field at its real field.Offset.
+// Field names come from an `hdf5:"colname"` tag, falling back to the Go
+// field name.
+func NewCompoundTypeFromStruct(rt reflect.Type) (*Datatype, error) {
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("hdf5: NewCompoundTypeFromStruct (non-struct %s)", rt)
+	}
+
+	tid := C.H5Tcreate(C.H5T_COMPOUND, C.size_t(rt.Size()))
+	if err := checkID(tid); err != nil {
+		return nil, err
+	}
+	dtype := newDatatype(tid)
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := parseTag(field.Tag.Get("hdf5"))
+		if tag.skip {
+			continue
+		}
+		name := fieldName(field, tag)
+
+		member, err := compoundMemberType(field.Type)
+		if err != nil {
+			dtype.Close()
+			return nil, fmt.Errorf("hdf5: NewCompoundTypeFromStruct field %q: %v", name, err)
+		}
+
+		c_name := C.CString(name)
+		rc := C.H5Tinsert(dtype.id, c_name, C.size_t(field.Offset), member.id)
+		C.free(unsafe.Pointer(c_name))
+		member.Close()
+		if err := h5err(rc); err != nil {
+			dtype.Close()
+			return nil, fmt.Errorf("hdf5: NewCompoundTypeFromStruct field %q: %v", name, err)
+		}
+	}
+
+	return dtype, nil
+}
+
+// datatypeFor returns the Datatype to use for I/O against a value of Go type
+// rt: a compound type built from field offsets when rt is a struct, and the
+// ordinary NewDataTypeFromType result otherwise.
+func datatypeFor(rt reflect.Type) (*Datatype, error) {
+	if rt.Kind() == reflect.Struct {
+		return NewCompoundTypeFromStruct(rt)
+	}
+	return NewDataTypeFromType(rt)
+}
+
+// compoundMemberType returns the Datatype for a single compound member,
+// recursing for nested structs and building an H5T_ARRAY for fixed-size
+// array fields.
+func compoundMemberType(ft reflect.Type) (*Datatype, error) {
+	switch ft.Kind() {
+	case reflect.Struct:
+		return NewCompoundTypeFromStruct(ft)
+
+	case reflect.Array:
+		base, err := NewDataTypeFromType(ft.Elem())
+		if err != nil {
+			return nil, err
+		}
+		defer base.Close()
+
+		dims := [1]C.hsize_t{C.hsize_t(ft.Len())}
+		tid := C.H5Tarray_create2(base.id, 1, &dims[0])
+		if err := checkID(tid); err != nil {
+			return nil, err
+		}
+		return newDatatype(tid), nil
+
+	default:
+		return NewDataTypeFromType(ft)
+	}
+}
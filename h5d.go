@@ -61,6 +61,8 @@ func (s *Dataset) Space() *Dataspace {
 }
 
 // ReadSubset reads a subset of raw data from a dataset into a buffer.
+// Variable-length types (VL strings, H5T_VLEN) are rejected; use ReadVL
+// for those.
 func (s *Dataset) ReadSubset(data interface{}, memspace, filespace *Dataspace) error {
 
 	v := reflect.ValueOf(data)
@@ -68,18 +70,27 @@ func (s *Dataset) ReadSubset(data interface{}, memspace, filespace *Dataspace) e
 		return fmt.Errorf("Attribute: Read (non-pointer %v )", v.Kind())
 	}
 
+	dtype, err := s.Datatype()
+	if err != nil {
+		return err
+	}
+	isVL := isVariableLength(dtype)
+	dtype.Close()
+	if isVL {
+		return fmt.Errorf("Dataset: ReadSubset: variable-length type, use ReadVL")
+	}
+
 	var addr uintptr
-	var err error
 	var typ *Datatype
 
 	switch v.Elem().Kind() {
 
 	case reflect.Array:
-		typ, err = NewDataTypeFromType(v.Type().Elem().Elem())
+		typ, err = datatypeFor(v.Type().Elem().Elem())
 		addr = v.Elem().UnsafeAddr()
 
 	case reflect.Slice:
-		typ, err = NewDataTypeFromType(v.Type().Elem().Elem())
+		typ, err = datatypeFor(v.Type().Elem().Elem())
 		addr = (*reflect.SliceHeader)(unsafe.Pointer(v.Elem().UnsafeAddr())).Data
 
 	case reflect.String:
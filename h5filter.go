@@ -0,0 +1,176 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import "fmt"
+
+// Filter is implemented by the dataset creation filters applied, in order,
+// to a PropList via Filter.apply.
+type Filter interface {
+	apply(dcpl *PropList) error
+}
+
+// Gzip applies the deflate (gzip) filter at the given compression level
+// (0-9).
+type Gzip int
+
+func (g Gzip) apply(dcpl *PropList) error {
+	return h5err(C.H5Pset_deflate(dcpl.id, C.uint(g)))
+}
+
+// Szip applies the szip filter with the given options mask and pixels-per-
+// block.
+type Szip struct {
+	OptionMask     uint
+	PixelsPerBlock uint
+}
+
+func (s Szip) apply(dcpl *PropList) error {
+	return h5err(C.H5Pset_szip(dcpl.id, C.uint(s.OptionMask), C.uint(s.PixelsPerBlock)))
+}
+
+// Shuffle applies the byte-shuffle filter, which commonly improves the
+// ratio achieved by a following compression filter.
+type Shuffle struct{}
+
+func (Shuffle) apply(dcpl *PropList) error {
+	return h5err(C.H5Pset_shuffle(dcpl.id))
+}
+
+// Fletcher32 applies the Fletcher32 checksum filter.
+type Fletcher32 struct{}
+
+func (Fletcher32) apply(dcpl *PropList) error {
+	return h5err(C.H5Pset_fletcher32(dcpl.id))
+}
+
+// NBit applies the N-bit packing filter. The dataset's datatype must
+// already be set up with the desired precision/offset before this filter is
+// applied.
+type NBit struct{}
+
+func (NBit) apply(dcpl *PropList) error {
+	return h5err(C.H5Pset_nbit(dcpl.id))
+}
+
+// ScaleOffsetType selects the datatype class a ScaleOffset filter packs.
+type ScaleOffsetType int
+
+const (
+	ScaleOffsetInt ScaleOffsetType = iota
+	ScaleOffsetFloatDScale
+)
+
+func (t ScaleOffsetType) h5() C.H5Z_SO_scale_type_t {
+	if t == ScaleOffsetFloatDScale {
+		return C.H5Z_SO_FLOAT_DSCALE
+	}
+	return C.H5Z_SO_INT
+}
+
+// ScaleOffset applies the scale-offset filter for the given datatype class
+// and scale factor.
+type ScaleOffset struct {
+	ScaleType   ScaleOffsetType
+	ScaleFactor int
+}
+
+func (s ScaleOffset) apply(dcpl *PropList) error {
+	return h5err(C.H5Pset_scaleoffset(dcpl.id, s.ScaleType.h5(), C.int(s.ScaleFactor)))
+}
+
+// CreateChunkedDataset creates a new chunked dataset named name with the
+// given dtype, dims, and chunk shape chunkDims, applying each filter in the
+// order given.
+func (f *File) CreateChunkedDataset(name string, dtype *Datatype, dims, chunkDims []uint, filters ...Filter) (*Dataset, error) {
+	return createChunkedDataset(f.id, name, dtype, dims, chunkDims, filters...)
+}
+
+// CreateChunkedDataset creates a new chunked dataset named name within the
+// group, applying each filter in the order given.
+func (g *Group) CreateChunkedDataset(name string, dtype *Datatype, dims, chunkDims []uint, filters ...Filter) (*Dataset, error) {
+	return createChunkedDataset(g.id, name, dtype, dims, chunkDims, filters...)
+}
+
+func createChunkedDataset(id C.hid_t, name string, dtype *Datatype, dims, chunkDims []uint, filters ...Filter) (*Dataset, error) {
+	if len(chunkDims) != len(dims) {
+		return nil, fmt.Errorf("hdf5: CreateChunkedDataset: chunk rank %d does not match dataspace rank %d", len(chunkDims), len(dims))
+	}
+
+	dspace, err := CreateSimpleDataspace(dims, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer dspace.Close()
+
+	dcpl, err := NewPropList(P_CLS_DATASET_CREATE)
+	if err != nil {
+		return nil, err
+	}
+	defer dcpl.Close()
+
+	if err := dcpl.SetChunk(chunkDims); err != nil {
+		return nil, err
+	}
+	for _, filt := range filters {
+		if err := filt.apply(dcpl); err != nil {
+			return nil, fmt.Errorf("hdf5: CreateChunkedDataset: applying filter: %v", err)
+		}
+	}
+
+	return createDataset(id, name, dtype, dspace, dcpl)
+}
+
+// SetExtent resizes a chunked dataset's current dimensions to dims, via
+// H5Dset_extent.
+func (s *Dataset) SetExtent(dims []uint) error {
+	cdims := make([]C.hsize_t, len(dims))
+	for i, d := range dims {
+		cdims[i] = C.hsize_t(d)
+	}
+	var p *C.hsize_t
+	if len(cdims) > 0 {
+		p = &cdims[0]
+	}
+	return h5err(C.H5Dset_extent(s.id, p))
+}
+
+// SelectHyperslab selects a hyperslab region of the dataspace described by
+// start, stride, count, and block, via H5Sselect_hyperslab. A nil stride or
+// block falls back to the HDF5 default (contiguous, unit stride).
+func (d *Dataspace) SelectHyperslab(start, stride, count, block []uint) error {
+	cStart := toHsize(start)
+	cStride := toHsizePtr(stride)
+	cCount := toHsize(count)
+	cBlock := toHsizePtr(block)
+
+	var startPtr, countPtr *C.hsize_t
+	if len(cStart) > 0 {
+		startPtr = &cStart[0]
+	}
+	if len(cCount) > 0 {
+		countPtr = &cCount[0]
+	}
+
+	return h5err(C.H5Sselect_hyperslab(d.id, C.H5S_SELECT_SET, startPtr, cStride, countPtr, cBlock))
+}
+
+func toHsize(dims []uint) []C.hsize_t {
+	out := make([]C.hsize_t, len(dims))
+	for i, d := range dims {
+		out[i] = C.hsize_t(d)
+	}
+	return out
+}
+
+func toHsizePtr(dims []uint) *C.hsize_t {
+	if dims == nil {
+		return nil
+	}
+	cdims := toHsize(dims)
+	if len(cdims) == 0 {
+		return nil
+	}
+	return &cdims[0]
+}
@@ -0,0 +1,244 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+//
+// extern herr_t goGroupIterate(hid_t g_id, char *name, H5L_info2_t *info, uintptr_t tok);
+// extern herr_t goAttrIterate(hid_t loc_id, char *attr_name, H5A_info_t *ainfo, uintptr_t tok);
+//
+// static herr_t groupIterateTrampoline(hid_t g_id, const char *name, const H5L_info2_t *info, void *op_data) {
+//     return goGroupIterate(g_id, (char *)name, (H5L_info2_t *)info, (uintptr_t)op_data);
+// }
+//
+// static herr_t attrIterateTrampoline(hid_t loc_id, const char *attr_name, const H5A_info_t *ainfo, void *op_data) {
+//     return goAttrIterate(loc_id, (char *)attr_name, (H5A_info_t *)ainfo, (uintptr_t)op_data);
+// }
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// ObjectInfo describes a single member encountered while iterating a group
+// with Group.Iterate.
+type ObjectInfo struct {
+	Type ObjectType
+
+	parentID C.hid_t
+	name     string
+}
+
+// Open opens the object this ObjectInfo describes, via H5Oopen (which
+// transparently resolves soft/external links). The caller is responsible
+// for closing the returned handle.
+func (i ObjectInfo) Open() (Identifier, error) {
+	c_name := C.CString(i.name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	hid := C.H5Oopen(i.parentID, c_name, C.H5P_DEFAULT)
+	if err := checkID(hid); err != nil {
+		return Identifier{}, err
+	}
+	return Identifier{id: hid}, nil
+}
+
+// ObjectType classifies the kind of object an ObjectInfo refers to.
+type ObjectType int
+
+const (
+	TypeGroup ObjectType = iota
+	TypeDataset
+	TypeNamedDatatype
+	TypeExternalLink
+)
+
+// objectType resolves the real object type of a group member named name.
+// H5L_info2_t.typ only distinguishes hard/soft/external links, so external
+// links are reported directly and hard links are resolved with
+// H5Oget_info_by_name2.
+func objectType(g_id C.hid_t, name *C.char, info *C.H5L_info2_t) (ObjectType, error) {
+	if info.typ == C.H5L_TYPE_EXTERNAL {
+		return TypeExternalLink, nil
+	}
+
+	var oinfo C.H5O_info2_t
+	if err := h5err(C.H5Oget_info_by_name2(g_id, name, &oinfo, C.H5O_INFO_BASIC, C.H5P_DEFAULT)); err != nil {
+		return 0, err
+	}
+	switch oinfo.typ {
+	case C.H5O_TYPE_DATASET:
+		return TypeDataset, nil
+	case C.H5O_TYPE_NAMED_DATATYPE:
+		return TypeNamedDatatype, nil
+	default:
+		return TypeGroup, nil
+	}
+}
+
+// iterCallbacks and attrIterCallbacks map an opaque token passed through
+// op_data back to the Go closure that started the iteration, since cgo
+// can't pass a Go func value as a C void*.
+var (
+	iterMu        sync.Mutex
+	iterCallbacks = map[uintptr]func(name string, info ObjectInfo) error{}
+	iterNextToken uintptr
+
+	attrIterMu        sync.Mutex
+	attrIterCallbacks = map[uintptr]func(name string, a *Attribute) error{}
+	attrIterNextToken uintptr
+)
+
+func registerIterCallback(fn func(name string, info ObjectInfo) error) uintptr {
+	iterMu.Lock()
+	defer iterMu.Unlock()
+	iterNextToken++
+	iterCallbacks[iterNextToken] = fn
+	return iterNextToken
+}
+
+func unregisterIterCallback(tok uintptr) {
+	iterMu.Lock()
+	defer iterMu.Unlock()
+	delete(iterCallbacks, tok)
+}
+
+func registerAttrIterCallback(fn func(name string, a *Attribute) error) uintptr {
+	attrIterMu.Lock()
+	defer attrIterMu.Unlock()
+	attrIterNextToken++
+	attrIterCallbacks[attrIterNextToken] = fn
+	return attrIterNextToken
+}
+
+func unregisterAttrIterCallback(tok uintptr) {
+	attrIterMu.Lock()
+	defer attrIterMu.Unlock()
+	delete(attrIterCallbacks, tok)
+}
+
+// iterErrs stashes the Go error returned by a callback, keyed by token,
+// since H5L_iterate2_t/H5A_operator2_t can only propagate an herr_t.
+var (
+	iterErrMu sync.Mutex
+	iterErrs  = map[uintptr]error{}
+)
+
+func stashIterErr(tok uintptr, err error) {
+	iterErrMu.Lock()
+	iterErrs[tok] = err
+	iterErrMu.Unlock()
+}
+
+func takeIterErr(tok uintptr) error {
+	iterErrMu.Lock()
+	defer iterErrMu.Unlock()
+	err := iterErrs[tok]
+	delete(iterErrs, tok)
+	return err
+}
+
+// Iterate calls fn once for each member of the group, in HDF5's native link
+// order, stopping early (and returning fn's error) if fn returns non-nil.
+func (g *Group) Iterate(fn func(name string, info ObjectInfo) error) error {
+	tok := registerIterCallback(fn)
+	defer unregisterIterCallback(tok)
+
+	var idx C.hsize_t
+	rc := C.H5Literate2(g.id, C.H5_INDEX_NAME, C.H5_ITER_NATIVE, &idx,
+		(C.H5L_iterate2_t)(unsafe.Pointer(C.groupIterateTrampoline)), unsafe.Pointer(tok))
+
+	if err := takeIterErr(tok); err != nil {
+		return err
+	}
+	if rc < 0 {
+		return h5err(rc)
+	}
+	return nil
+}
+
+//export goGroupIterate
+func goGroupIterate(g_id C.hid_t, c_name *C.char, info *C.H5L_info2_t, tok C.uintptr_t) C.herr_t {
+	iterMu.Lock()
+	fn, ok := iterCallbacks[uintptr(tok)]
+	iterMu.Unlock()
+	if !ok {
+		return -1
+	}
+
+	typ, err := objectType(g_id, c_name, info)
+	if err != nil {
+		stashIterErr(uintptr(tok), err)
+		return -1
+	}
+
+	name := C.GoString(c_name)
+	oinfo := ObjectInfo{Type: typ, parentID: g_id, name: name}
+	if err := fn(name, oinfo); err != nil {
+		stashIterErr(uintptr(tok), err)
+		return -1
+	}
+	return 0
+}
+
+// iterateAttributes calls fn once for each attribute attached to id, in
+// HDF5's native creation order, stopping early (and returning fn's error)
+// if fn returns non-nil. It is backed by H5Aiterate2 via a cgo trampoline
+// registered as the H5A_operator2_t callback.
+func iterateAttributes(id C.hid_t, fn func(name string, a *Attribute) error) error {
+	tok := registerAttrIterCallback(fn)
+	defer unregisterAttrIterCallback(tok)
+
+	var idx C.hsize_t
+	rc := C.H5Aiterate2(id, C.H5_INDEX_CRT_ORDER, C.H5_ITER_NATIVE, &idx,
+		(C.H5A_operator2_t)(unsafe.Pointer(C.attrIterateTrampoline)), unsafe.Pointer(tok))
+
+	if err := takeIterErr(tok); err != nil {
+		return err
+	}
+	return h5err(rc)
+}
+
+// IterateAttributes enumerates the group's attributes; see iterateAttributes.
+func (g *Group) IterateAttributes(fn func(name string, a *Attribute) error) error {
+	return iterateAttributes(g.id, fn)
+}
+
+// IterateAttributes enumerates the dataset's attributes; see iterateAttributes.
+func (s *Dataset) IterateAttributes(fn func(name string, a *Attribute) error) error {
+	return iterateAttributes(s.id, fn)
+}
+
+// IterateAttributes enumerates the file's attributes; see iterateAttributes.
+func (f *File) IterateAttributes(fn func(name string, a *Attribute) error) error {
+	return iterateAttributes(f.id, fn)
+}
+
+// IterateAttributes enumerates the attribute's own attributes; see iterateAttributes.
+func (a *Attribute) IterateAttributes(fn func(name string, a2 *Attribute) error) error {
+	return iterateAttributes(a.id, fn)
+}
+
+//export goAttrIterate
+func goAttrIterate(loc_id C.hid_t, c_name *C.char, ainfo *C.H5A_info_t, tok C.uintptr_t) C.herr_t {
+	attrIterMu.Lock()
+	fn, ok := attrIterCallbacks[uintptr(tok)]
+	attrIterMu.Unlock()
+	if !ok {
+		return -1
+	}
+
+	name := C.GoString(c_name)
+	attr, err := openAttribute(loc_id, name)
+	if err != nil {
+		stashIterErr(uintptr(tok), err)
+		return -1
+	}
+	defer attr.Close()
+
+	if err := fn(name, attr); err != nil {
+		stashIterErr(uintptr(tok), err)
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,294 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagInfo is the parsed form of an `hdf5:"..."` struct tag.
+type tagInfo struct {
+	name     string
+	attr     bool
+	skip     bool
+	chunk    []uint
+	compress string
+	level    int
+	fill     string
+}
+
+// parseTag splits a struct tag of the form
+// `hdf5:"name,attr,chunk=64x64,compress=gzip:6,fill=0"` into its parts.
+// A "-" name skips the field, mirroring encoding/json; an empty name leaves
+// t.name empty, so fieldName falls back to the Go field name.
+func parseTag(raw string) tagInfo {
+	var t tagInfo
+	parts := strings.Split(raw, ",")
+	if len(parts) == 0 {
+		return t
+	}
+	t.name = parts[0]
+	if t.name == "-" {
+		t.skip = true
+		return t
+	}
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "attr":
+			t.attr = true
+		case strings.HasPrefix(opt, "chunk="):
+			t.chunk = parseChunkDims(strings.TrimPrefix(opt, "chunk="))
+		case strings.HasPrefix(opt, "compress="):
+			spec := strings.TrimPrefix(opt, "compress=")
+			if i := strings.IndexByte(spec, ':'); i >= 0 {
+				t.compress = spec[:i]
+				t.level, _ = strconv.Atoi(spec[i+1:])
+			} else {
+				t.compress = spec
+			}
+		case strings.HasPrefix(opt, "fill="):
+			t.fill = strings.TrimPrefix(opt, "fill=")
+		}
+	}
+	return t
+}
+
+// parseChunkDims parses a dimension spec like "64x64" into []uint{64, 64}.
+func parseChunkDims(spec string) []uint {
+	if spec == "" {
+		return nil
+	}
+	fields := strings.Split(spec, "x")
+	dims := make([]uint, 0, len(fields))
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n <= 0 {
+			continue
+		}
+		dims = append(dims, uint(n))
+	}
+	return dims
+}
+
+// fieldName returns the tag-provided name for a struct field, falling back
+// to the Go field name when no tag (or an empty name) is present.
+func fieldName(f reflect.StructField, t tagInfo) string {
+	if t.name != "" {
+		return t.name
+	}
+	return f.Name
+}
+
+// marshalTarget is satisfied by the identifiers that can host datasets,
+// attributes, and child groups: *File, *Group.
+type marshalTarget interface {
+	CreateGroup(name string) (*Group, error)
+	CreateDataset(name string, dtype *Datatype, dspace *Dataspace) (*Dataset, error)
+	CreateDatasetWith(name string, dtype *Datatype, dspace *Dataspace, dcpl *PropList) (*Dataset, error)
+	CreateAttribute(name string, dtype *Datatype, dspace *Dataspace) (*Attribute, error)
+}
+
+// Marshal writes the fields of v (a pointer to struct) as datasets and
+// attributes of the File, per `hdf5` struct tags on each field.
+func (f *File) Marshal(v interface{}) error {
+	return marshalStruct(f, v)
+}
+
+// Unmarshal reads datasets and attributes of the File into v, the inverse
+// of Marshal.
+func (f *File) Unmarshal(v interface{}) error {
+	return unmarshalStruct((*Group)(f), v)
+}
+
+// Marshal writes the fields of v (a pointer to struct) as datasets and
+// attributes of the Group, per `hdf5` struct tags on each field.
+func (g *Group) Marshal(v interface{}) error {
+	return marshalStruct(g, v)
+}
+
+// Unmarshal reads datasets and attributes of the Group into v, the inverse
+// of Marshal.
+func (g *Group) Unmarshal(v interface{}) error {
+	return unmarshalStruct(g, v)
+}
+
+func marshalStruct(target marshalTarget, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("hdf5: Marshal (non-pointer-to-struct %T)", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		tag := parseTag(field.Tag.Get("hdf5"))
+		if tag.skip || !fv.CanInterface() {
+			continue
+		}
+		name := fieldName(field, tag)
+
+		if fv.Kind() == reflect.Struct {
+			child, err := target.CreateGroup(name)
+			if err != nil {
+				return fmt.Errorf("hdf5: Marshal field %q: %v", name, err)
+			}
+			if err := marshalStruct(child, fv.Addr().Interface()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := marshalField(target, name, fv, tag); err != nil {
+			return fmt.Errorf("hdf5: Marshal field %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func marshalField(target marshalTarget, name string, fv reflect.Value, tag tagInfo) error {
+	elemType := fv.Type()
+	if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array {
+		elemType = elemType.Elem()
+	}
+	dtype, err := datatypeFor(elemType)
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+
+	dspace, err := dataspaceForField(fv)
+	if err != nil {
+		return err
+	}
+	defer dspace.Close()
+
+	if tag.attr {
+		attr, err := target.CreateAttribute(name, dtype, dspace)
+		if err != nil {
+			return err
+		}
+		defer attr.Close()
+		return attr.Write(fv.Addr().Interface())
+	}
+
+	var dset *Dataset
+	if len(tag.chunk) > 0 || tag.compress != "" {
+		dcpl, err := newChunkedPropList(tag)
+		if err != nil {
+			return err
+		}
+		defer dcpl.Close()
+		dset, err = target.CreateDatasetWith(name, dtype, dspace, dcpl)
+		if err != nil {
+			return err
+		}
+	} else {
+		dset, err = target.CreateDataset(name, dtype, dspace)
+		if err != nil {
+			return err
+		}
+	}
+	defer dset.Close()
+	return dset.Write(fv.Addr().Interface())
+}
+
+// dataspaceForField picks a dataspace matching fv: scalar for plain values,
+// simple/1-D sized by Len() for slices and arrays.
+func dataspaceForField(fv reflect.Value) (*Dataspace, error) {
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return CreateSimpleDataspace([]uint{uint(fv.Len())}, nil)
+	default:
+		return CreateScalarDataspace()
+	}
+}
+
+// newChunkedPropList builds an H5P_DATASET_CREATE PropList applying the
+// chunk dimensions and compression filter described by tag.
+func newChunkedPropList(tag tagInfo) (*PropList, error) {
+	dcpl, err := NewPropList(P_CLS_DATASET_CREATE)
+	if err != nil {
+		return nil, err
+	}
+	if len(tag.chunk) > 0 {
+		if err := dcpl.SetChunk(tag.chunk); err != nil {
+			dcpl.Close()
+			return nil, err
+		}
+	}
+	switch tag.compress {
+	case "gzip":
+		if err := dcpl.SetDeflate(tag.level); err != nil {
+			dcpl.Close()
+			return nil, err
+		}
+	case "szip":
+		if err := dcpl.SetSzip(tag.level); err != nil {
+			dcpl.Close()
+			return nil, err
+		}
+	}
+	return dcpl, nil
+}
+
+func unmarshalStruct(target *Group, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("hdf5: Unmarshal (non-pointer-to-struct %T)", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		tag := parseTag(field.Tag.Get("hdf5"))
+		if tag.skip || !fv.CanAddr() || !fv.CanInterface() {
+			continue
+		}
+		name := fieldName(field, tag)
+
+		if fv.Kind() == reflect.Struct {
+			child, err := target.OpenGroup(name)
+			if err != nil {
+				return fmt.Errorf("hdf5: Unmarshal field %q: %v", name, err)
+			}
+			err = unmarshalStruct(child, fv.Addr().Interface())
+			child.Close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag.attr {
+			attr, err := target.OpenAttribute(name)
+			if err != nil {
+				return fmt.Errorf("hdf5: Unmarshal field %q: %v", name, err)
+			}
+			err = attr.Read(fv.Addr().Interface())
+			attr.Close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		dset, err := target.OpenDataset(name)
+		if err != nil {
+			return fmt.Errorf("hdf5: Unmarshal field %q: %v", name, err)
+		}
+		err = dset.Read(fv.Addr().Interface())
+		dset.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package hdf5
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want tagInfo
+	}{
+		{"", tagInfo{}},
+		{"-", tagInfo{skip: true}},
+		{"temp", tagInfo{name: "temp"}},
+		{"temp,attr", tagInfo{name: "temp", attr: true}},
+		{"data,chunk=64x64", tagInfo{name: "data", chunk: []uint{64, 64}}},
+		{"data,compress=gzip:6", tagInfo{name: "data", compress: "gzip", level: 6}},
+		{"data,compress=szip", tagInfo{name: "data", compress: "szip"}},
+		{"data,fill=0", tagInfo{name: "data", fill: "0"}},
+	}
+
+	for _, c := range cases {
+		got := parseTag(c.raw)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseTag(%q) = %+v, want %+v", c.raw, got, c.want)
+		}
+	}
+}
+
+func TestParseChunkDims(t *testing.T) {
+	cases := []struct {
+		spec string
+		want []uint
+	}{
+		{"", nil},
+		{"64", []uint{64}},
+		{"64x64", []uint{64, 64}},
+		{"4x8x16", []uint{4, 8, 16}},
+		{"64xbad", []uint{64}},
+	}
+
+	for _, c := range cases {
+		got := parseChunkDims(c.spec)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseChunkDims(%q) = %v, want %v", c.spec, got, c.want)
+		}
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	type S struct {
+		Temp     float64 `hdf5:"temperature"`
+		Humidity float64
+	}
+	rt := reflect.TypeOf(S{})
+
+	tagged := rt.Field(0)
+	if got := fieldName(tagged, parseTag(tagged.Tag.Get("hdf5"))); got != "temperature" {
+		t.Errorf("fieldName(Temp) = %q, want %q", got, "temperature")
+	}
+
+	untagged := rt.Field(1)
+	if got := fieldName(untagged, parseTag(untagged.Tag.Get("hdf5"))); got != "Humidity" {
+		t.Errorf("fieldName(Humidity) = %q, want %q", got, "Humidity")
+	}
+}
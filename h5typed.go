@@ -0,0 +1,156 @@
+package hdf5
+
+// #include "hdf5.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// typeCache memoizes the Datatype built for a given reflect.Type so repeated
+// ReadTyped/WriteTyped calls on the same T don't re-derive it on every call.
+var typeCache sync.Map // map[reflect.Type]*Datatype
+
+func cachedDataType(rt reflect.Type) (*Datatype, error) {
+	if v, ok := typeCache.Load(rt); ok {
+		return v.(*Datatype), nil
+	}
+	if err := checkFixedLayout(rt); err != nil {
+		return nil, err
+	}
+	dtype, err := datatypeFor(rt)
+	if err != nil {
+		return nil, err
+	}
+	if actual, loaded := typeCache.LoadOrStore(rt, dtype); loaded {
+		dtype.Close()
+		return actual.(*Datatype), nil
+	}
+	return dtype, nil
+}
+
+// checkFixedLayout rejects the reflect kinds that don't have a stable
+// in-memory layout HDF5 can read/write directly: strings, slices, maps,
+// chans, funcs, interfaces, and pointers, including inside array elements
+// and nested struct fields. Writing HDF5 data directly over one of these
+// (e.g. a string header) would corrupt a pointer the GC later chases.
+func checkFixedLayout(rt reflect.Type) error {
+	switch rt.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.Ptr, reflect.UnsafePointer:
+		return fmt.Errorf("hdf5: Typed: unsupported kind %s (%s is not a fixed-layout type)", rt.Kind(), rt)
+	case reflect.Array:
+		return checkFixedLayout(rt.Elem())
+	case reflect.Struct:
+		for i := 0; i < rt.NumField(); i++ {
+			if err := checkFixedLayout(rt.Field(i).Type); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReadTyped reads the entirety of d into a freshly allocated []T. T must be
+// a fixed-layout type: numeric, an array, or a struct of the same.
+func ReadTyped[T any](d *Dataset) ([]T, error) {
+	dspace := d.Space()
+	if dspace == nil {
+		return nil, fmt.Errorf("hdf5: ReadTyped: couldn't get dataspace for dataset %q", d.Name())
+	}
+	defer dspace.Close()
+
+	n, err := dspace.SimpleExtentNPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, n)
+	if n == 0 {
+		return out, nil
+	}
+
+	var zero T
+	rt := reflect.TypeOf(zero)
+	dtype, err := cachedDataType(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(out))
+	if err := h5err(C.H5Dread(d.id, dtype.id, 0, 0, 0, ptr)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WriteTyped writes v to d in its entirety. T must be a fixed-layout type:
+// numeric, an array, or a struct of the same.
+func WriteTyped[T any](d *Dataset, v []T) error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	var zero T
+	rt := reflect.TypeOf(zero)
+	dtype, err := cachedDataType(rt)
+	if err != nil {
+		return err
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(v))
+	return h5err(C.H5Dwrite(d.id, dtype.id, 0, 0, 0, ptr))
+}
+
+// ReadAttrTyped reads attribute a into a freshly allocated []T. T must be a
+// fixed-layout type: numeric, an array, or a struct of the same.
+func ReadAttrTyped[T any](a *Attribute) ([]T, error) {
+	dspace := a.Space()
+	if dspace == nil {
+		return nil, fmt.Errorf("hdf5: ReadAttrTyped: couldn't get dataspace for attribute")
+	}
+	defer dspace.Close()
+
+	n, err := dspace.SimpleExtentNPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, n)
+	if n == 0 {
+		return out, nil
+	}
+
+	var zero T
+	rt := reflect.TypeOf(zero)
+	dtype, err := cachedDataType(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(out))
+	if err := h5err(C.H5Aread(a.id, dtype.id, ptr)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WriteAttrTyped writes v to attribute a in its entirety. T must be a
+// fixed-layout type: numeric, an array, or a struct of the same.
+func WriteAttrTyped[T any](a *Attribute, v []T) error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	var zero T
+	rt := reflect.TypeOf(zero)
+	dtype, err := cachedDataType(rt)
+	if err != nil {
+		return err
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(v))
+	return h5err(C.H5Awrite(a.id, dtype.id, ptr))
+}
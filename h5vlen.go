@@ -0,0 +1,228 @@
+package hdf5
+
+// #include "hdf5.h"
+// #include <stdlib.h>
+// #include <string.h>
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// isVariableLength reports whether dtype is a variable-length HDF5 type:
+// either H5T_VLEN (VL sequences) or a VL string.
+func isVariableLength(dtype *Datatype) bool {
+	if C.H5Tget_class(dtype.id) == C.H5T_VLEN {
+		return true
+	}
+	return C.H5Tis_variable_str(dtype.id) > 0
+}
+
+// ReadVL reads a variable-length dataset into data, a pointer to a slice of
+// []T (for H5T_VLEN) or a slice of string (for VL strings), reclaiming the
+// per-element buffers HDF5 allocates via H5Dvlen_reclaim.
+func (s *Dataset) ReadVL(data interface{}) error {
+	dtype, err := s.Datatype()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+
+	dspace := s.Space()
+	if dspace == nil {
+		return fmt.Errorf("hdf5: ReadVL: couldn't get dataspace for dataset %q", s.Name())
+	}
+	defer dspace.Close()
+
+	return readVL(s.id, dtype, dspace, data, func(tid, sid C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dread(s.id, tid, 0, 0, 0, buf))
+	})
+}
+
+// WriteVL writes data, a slice of []T or string, to a variable-length
+// dataset, building the hvl_t{len,p} entries HDF5 expects.
+func (s *Dataset) WriteVL(data interface{}) error {
+	dtype, err := s.Datatype()
+	if err != nil {
+		return err
+	}
+	defer dtype.Close()
+
+	return writeVL(dtype, data, func(tid C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Dwrite(s.id, tid, 0, 0, 0, buf))
+	})
+}
+
+// ReadVL reads a variable-length attribute into data, the attribute analog
+// of Dataset.ReadVL.
+func (a *Attribute) ReadVL(data interface{}) error {
+	dtype := a.GetType()
+	defer dtype.Close()
+
+	dspace := a.Space()
+	if dspace == nil {
+		return fmt.Errorf("hdf5: ReadVL: couldn't get dataspace for attribute")
+	}
+	defer dspace.Close()
+
+	return readVL(a.id, dtype, dspace, data, func(tid, sid C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Aread(a.id, tid, buf))
+	})
+}
+
+// WriteVL writes data to a variable-length attribute, the attribute analog
+// of Dataset.WriteVL.
+func (a *Attribute) WriteVL(data interface{}) error {
+	dtype := a.GetType()
+	defer dtype.Close()
+
+	return writeVL(dtype, data, func(tid C.hid_t, buf unsafe.Pointer) error {
+		return h5err(C.H5Awrite(a.id, tid, buf))
+	})
+}
+
+// readVL drives the VL read + reclaim sequence shared by Dataset.ReadVL and
+// Attribute.ReadVL; read performs the actual H5Dread/H5Aread call.
+func readVL(id C.hid_t, dtype *Datatype, dspace *Dataspace, data interface{}, read func(tid, sid C.hid_t, buf unsafe.Pointer) error) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("hdf5: ReadVL (need pointer-to-slice, got %T)", data)
+	}
+
+	n, err := dspace.SimpleExtentNPoints()
+	if err != nil {
+		return err
+	}
+
+	isStr := C.H5Tis_variable_str(dtype.id) > 0
+
+	if isStr {
+		buf := make([]*C.char, n)
+		if n > 0 {
+			if err := read(dtype.id, dspace.id, unsafe.Pointer(&buf[0])); err != nil {
+				return err
+			}
+		}
+		out := make([]string, n)
+		for i, p := range buf {
+			if p != nil {
+				out[i] = C.GoString(p)
+			}
+		}
+		if n > 0 {
+			reclaimScratch(dtype, dspace, unsafe.Pointer(&buf[0]), n)
+		}
+		v.Elem().Set(reflect.ValueOf(out))
+		return nil
+	}
+
+	elemType := v.Elem().Type().Elem().Elem() // slice-of-slice element type
+	baseType, err := NewDataTypeFromType(elemType)
+	if err != nil {
+		return err
+	}
+	defer baseType.Close()
+
+	// The memory type must itself be H5T_VLEN so HDF5 fills buf with hvl_t
+	// entries, and so H5Dvlen_reclaim knows what to free.
+	vlTid := C.H5Tvlen_create(baseType.id)
+	if err := checkID(vlTid); err != nil {
+		return err
+	}
+	memType := newDatatype(vlTid)
+	defer memType.Close()
+
+	buf := make([]C.hvl_t, n)
+	if n > 0 {
+		if err := read(memType.id, dspace.id, unsafe.Pointer(&buf[0])); err != nil {
+			return err
+		}
+	}
+
+	out := reflect.MakeSlice(v.Elem().Type(), n, n)
+	for i, hv := range buf {
+		if hv.len == 0 {
+			continue
+		}
+		elem := reflect.MakeSlice(reflect.SliceOf(elemType), int(hv.len), int(hv.len))
+		dst := unsafe.Pointer(elem.Index(0).UnsafeAddr())
+		C.memmove(dst, hv.p, C.size_t(uintptr(hv.len)*elemType.Size()))
+		out.Index(i).Set(elem)
+	}
+	if n > 0 {
+		reclaimScratch(memType, dspace, unsafe.Pointer(&buf[0]), n)
+	}
+	v.Elem().Set(out)
+	return nil
+}
+
+// reclaimScratch releases the native buffers HDF5 allocated for a VL read
+// by calling H5Dvlen_reclaim against a scratch dataspace sized to n points.
+func reclaimScratch(dtype *Datatype, dspace *Dataspace, buf unsafe.Pointer, n int) {
+	dims := [1]C.hsize_t{C.hsize_t(n)}
+	sid := C.H5Screate_simple(1, &dims[0], nil)
+	defer C.H5Sclose(sid)
+	C.H5Dvlen_reclaim(dtype.id, sid, C.H5P_DEFAULT, buf)
+}
+
+// writeVL drives the VL write sequence shared by Dataset.WriteVL and
+// Attribute.WriteVL; write performs the actual H5Dwrite/H5Awrite call.
+func writeVL(dtype *Datatype, data interface{}, write func(tid C.hid_t, buf unsafe.Pointer) error) error {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("hdf5: WriteVL (need slice, got %T)", data)
+	}
+
+	if C.H5Tis_variable_str(dtype.id) > 0 {
+		cstrs := make([]*C.char, v.Len())
+		defer func() {
+			for _, p := range cstrs {
+				C.free(unsafe.Pointer(p))
+			}
+		}()
+		for i := 0; i < v.Len(); i++ {
+			cstrs[i] = C.CString(v.Index(i).String())
+		}
+		if len(cstrs) == 0 {
+			return write(dtype.id, nil)
+		}
+		return write(dtype.id, unsafe.Pointer(&cstrs[0]))
+	}
+
+	if v.Len() == 0 {
+		return write(dtype.id, nil)
+	}
+
+	// hvl_t.p is a C pointer, and the hvl_t array itself is handed to HDF5,
+	// so both must live in C-allocated memory: a Go slice of hvl_t storing
+	// Go pointers in elem.p would be a Go-pointer-to-Go-pointer crossing
+	// the cgo boundary, which cgo's pointer checks forbid.
+	elemSize := v.Type().Elem().Elem().Size()
+	cBuf := C.malloc(C.size_t(v.Len()) * C.size_t(unsafe.Sizeof(C.hvl_t{})))
+	defer C.free(cBuf)
+	hvlBuf := unsafe.Slice((*C.hvl_t)(cBuf), v.Len())
+
+	var payloads []unsafe.Pointer
+	defer func() {
+		for _, p := range payloads {
+			C.free(p)
+		}
+	}()
+
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Len() == 0 {
+			continue
+		}
+		n := C.size_t(elem.Len()) * C.size_t(elemSize)
+		payload := C.malloc(n)
+		payloads = append(payloads, payload)
+		C.memmove(payload, unsafe.Pointer(elem.Index(0).UnsafeAddr()), n)
+		hvlBuf[i].len = C.size_t(elem.Len())
+		hvlBuf[i].p = payload
+	}
+
+	return write(dtype.id, cBuf)
+}